@@ -0,0 +1,22 @@
+package activity
+
+// nonLocomotiveSports holds sports that are not location/distance based, e.g.
+// strength training, calisthenics, HIIT or yoga sessions recorded indoors
+// without meaningful GPS movement between records.
+var nonLocomotiveSports = map[string]struct{}{
+	"weight_training":   {},
+	"strength_training": {},
+	"calisthenics":      {},
+	"hiit":              {},
+	"yoga":              {},
+	"pilates":           {},
+}
+
+// IsNonLocomotive returns true when sport is a set/repetition based activity
+// that has no meaningful distance or pace to calculate, e.g. weightlifting or
+// a bodyweight HIIT session, as opposed to location-based sports like running
+// or cycling.
+func IsNonLocomotive(sport string) bool {
+	_, ok := nonLocomotiveSports[sport]
+	return ok
+}
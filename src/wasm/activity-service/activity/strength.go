@@ -0,0 +1,18 @@
+package activity
+
+import "time"
+
+// SetRecord represents a single set within a strength/repetition based
+// workout (weightlifting, calisthenics, HIIT, yoga, ...), as opposed to
+// Record which represents a location-based trackpoint. It is produced and
+// consumed alongside Record so that a single activity can mix both, e.g. a
+// HIIT session with GPS-tracked running intervals between lifting sets.
+type SetRecord struct {
+	Timestamp time.Time
+
+	Exercise    string   // e.g. "bench_press", "push_up", "squat"
+	Reps        *uint16  // number of repetitions performed in this set
+	Weight      *float64 // weight used, in kg
+	SetIndex    *uint16  // 1-based index of this set within its exercise
+	RestSeconds *float64 // rest duration preceding this set, in seconds
+}
@@ -0,0 +1,120 @@
+package schema
+
+import (
+	"encoding/xml"
+	"strconv"
+	"time"
+
+	"github.com/muktihari/openactivity-fit/activity"
+	"github.com/muktihari/openactivity-fit/kit"
+)
+
+// StrengthTrainingExtension represents Garmin's StrengthTraining Trackpoint extension, used to carry
+// set/repetition data for weightlifting and other non-location activities that would otherwise have
+// nothing to put in a Trackpoint's Position or DistanceMeters fields.
+type StrengthTrainingExtension struct {
+	Sets []*StrengthTrainingSet `xml:"Sets>Set"`
+}
+
+var _ xml.Unmarshaler = &StrengthTrainingExtension{}
+
+func (s *StrengthTrainingExtension) UnmarshalXML(dec *xml.Decoder, se xml.StartElement) error {
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch elem := token.(type) {
+		case xml.StartElement:
+			switch elem.Name.Local {
+			case "Set":
+				var set StrengthTrainingSet
+				if err := set.UnmarshalXML(dec, elem); err != nil {
+					return err
+				}
+				s.Sets = append(s.Sets, &set)
+			}
+		case xml.EndElement:
+			if elem == se.End() {
+				return nil
+			}
+		}
+	}
+}
+
+// ToSetRecords converts the Sets carried by this extension into activity.SetRecord values, so a decoded
+// Trackpoint's StrengthTraining extension survives round-trip parsing. TCX has no per-set timestamp, so
+// every record is stamped with the owning Trackpoint's Time; SetIndex is assigned 1-based from each set's
+// position within Sets. Called from Trackpoint.unmarshalTPX once a StrengthTraining extension has decoded.
+func (s *StrengthTrainingExtension) ToSetRecords(timestamp time.Time) []*activity.SetRecord {
+	if len(s.Sets) == 0 {
+		return nil
+	}
+
+	records := make([]*activity.SetRecord, len(s.Sets))
+	for i, set := range s.Sets {
+		records[i] = &activity.SetRecord{
+			Timestamp:   timestamp,
+			Exercise:    set.Exercise,
+			Reps:        kit.Ptr(set.Repetitions),
+			Weight:      kit.Ptr(set.Weight),
+			SetIndex:    kit.Ptr(uint16(i + 1)),
+			RestSeconds: kit.Ptr(set.RestSeconds),
+		}
+	}
+	return records
+}
+
+// StrengthTrainingSet represents a single <Set> within a StrengthTraining extension.
+type StrengthTrainingSet struct {
+	Exercise    string  // Name of the exercise performed, e.g. "Bench Press"
+	Repetitions uint16  // Number of repetitions performed
+	Weight      float64 // Weight used, in kilograms
+	RestSeconds float64 // Rest duration preceding this set, in seconds
+}
+
+var _ xml.Unmarshaler = &StrengthTrainingSet{}
+
+func (s *StrengthTrainingSet) UnmarshalXML(dec *xml.Decoder, se xml.StartElement) error {
+	var targetCharData string
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch elem := token.(type) {
+		case xml.StartElement:
+			targetCharData = elem.Name.Local
+		case xml.CharData:
+			switch targetCharData {
+			case "Exercise":
+				s.Exercise = string(elem)
+			case "Repetitions":
+				u, err := strconv.ParseUint(string(elem), 10, 16)
+				if err != nil {
+					return err
+				}
+				s.Repetitions = uint16(u)
+			case "Weight":
+				f, err := strconv.ParseFloat(string(elem), 64)
+				if err != nil {
+					return err
+				}
+				s.Weight = f
+			case "RestSeconds":
+				f, err := strconv.ParseFloat(string(elem), 64)
+				if err != nil {
+					return err
+				}
+				s.RestSeconds = f
+			}
+			targetCharData = ""
+		case xml.EndElement:
+			if elem == se.End() {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestStrengthTrainingExtension_UnmarshalXML(t *testing.T) {
+	data := `<StrengthTraining>
+		<Sets>
+			<Set>
+				<Exercise>Bench Press</Exercise>
+				<Repetitions>10</Repetitions>
+				<Weight>60</Weight>
+				<RestSeconds>90</RestSeconds>
+			</Set>
+			<Set>
+				<Exercise>Squat</Exercise>
+				<Repetitions>8</Repetitions>
+				<Weight>80</Weight>
+				<RestSeconds>120</RestSeconds>
+			</Set>
+		</Sets>
+	</StrengthTraining>`
+
+	var ext StrengthTrainingExtension
+	if err := xml.Unmarshal([]byte(data), &ext); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if len(ext.Sets) != 2 {
+		t.Fatalf("len(Sets) = %d, want 2", len(ext.Sets))
+	}
+
+	want := StrengthTrainingSet{Exercise: "Bench Press", Repetitions: 10, Weight: 60, RestSeconds: 90}
+	if *ext.Sets[0] != want {
+		t.Errorf("Sets[0] = %+v, want %+v", *ext.Sets[0], want)
+	}
+}
+
+func TestStrengthTrainingExtension_ToSetRecords(t *testing.T) {
+	ext := StrengthTrainingExtension{
+		Sets: []*StrengthTrainingSet{
+			{Exercise: "Bench Press", Repetitions: 10, Weight: 60, RestSeconds: 90},
+			{Exercise: "Squat", Repetitions: 8, Weight: 80, RestSeconds: 120},
+		},
+	}
+
+	ts := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	records := ext.ToSetRecords(ts)
+
+	if len(records) != len(ext.Sets) {
+		t.Fatalf("len(records) = %d, want %d", len(records), len(ext.Sets))
+	}
+
+	for i, rec := range records {
+		set := ext.Sets[i]
+
+		if !rec.Timestamp.Equal(ts) {
+			t.Errorf("records[%d].Timestamp = %v, want %v", i, rec.Timestamp, ts)
+		}
+		if rec.SetIndex == nil || *rec.SetIndex != uint16(i+1) {
+			t.Errorf("records[%d].SetIndex = %v, want %d", i, rec.SetIndex, i+1)
+		}
+		if rec.Exercise != set.Exercise {
+			t.Errorf("records[%d].Exercise = %q, want %q", i, rec.Exercise, set.Exercise)
+		}
+		if rec.Reps == nil || *rec.Reps != set.Repetitions {
+			t.Errorf("records[%d].Reps = %v, want %d", i, rec.Reps, set.Repetitions)
+		}
+		if rec.Weight == nil || *rec.Weight != set.Weight {
+			t.Errorf("records[%d].Weight = %v, want %v", i, rec.Weight, set.Weight)
+		}
+		if rec.RestSeconds == nil || *rec.RestSeconds != set.RestSeconds {
+			t.Errorf("records[%d].RestSeconds = %v, want %v", i, rec.RestSeconds, set.RestSeconds)
+		}
+	}
+
+	if got := (&StrengthTrainingExtension{}).ToSetRecords(ts); got != nil {
+		t.Errorf("ToSetRecords() on empty extension = %v, want nil", got)
+	}
+}
@@ -0,0 +1,252 @@
+package schema
+
+import (
+	"encoding/xml"
+	"strconv"
+	"time"
+
+	"github.com/muktihari/openactivity-fit/activity"
+	"github.com/muktihari/openactivity-fit/kit"
+)
+
+// Trackpoint represents a single <Trackpoint> element: position, distance, altitude, heart rate, cadence
+// and their TPX/StrengthTraining extensions. ToRecord and ToSetRecords convert a decoded Trackpoint into
+// the activity package's own types.
+type Trackpoint struct {
+	Time         time.Time
+	LatitudeDeg  *float64
+	LongitudeDeg *float64
+	AltitudeM    *float64
+	DistanceM    *float64
+	HeartRateBpm *uint8
+	Cadence      *uint8
+	Speed        *float64 // from the TPX extension, m/s
+	Watts        *uint16  // from the TPX extension
+
+	setRecords []*activity.SetRecord // from a StrengthTraining extension, if present
+}
+
+var _ xml.Unmarshaler = &Trackpoint{}
+
+func (t *Trackpoint) UnmarshalXML(dec *xml.Decoder, se xml.StartElement) error {
+	var targetCharData string
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch elem := token.(type) {
+		case xml.StartElement:
+			switch elem.Name.Local {
+			case "Position":
+				if err := t.unmarshalPosition(dec, elem); err != nil {
+					return err
+				}
+			case "HeartRateBpm":
+				if err := t.unmarshalHeartRateBpm(dec, elem); err != nil {
+					return err
+				}
+			case "Extensions":
+				if err := t.unmarshalExtensions(dec, elem); err != nil {
+					return err
+				}
+			default:
+				targetCharData = elem.Name.Local
+			}
+		case xml.CharData:
+			switch targetCharData {
+			case "Time":
+				parsed, err := time.Parse(time.RFC3339, string(elem))
+				if err != nil {
+					return err
+				}
+				t.Time = parsed
+			case "AltitudeMeters":
+				f, err := strconv.ParseFloat(string(elem), 64)
+				if err != nil {
+					return err
+				}
+				t.AltitudeM = kit.Ptr(f)
+			case "DistanceMeters":
+				f, err := strconv.ParseFloat(string(elem), 64)
+				if err != nil {
+					return err
+				}
+				t.DistanceM = kit.Ptr(f)
+			case "Cadence":
+				u, err := strconv.ParseUint(string(elem), 10, 8)
+				if err != nil {
+					return err
+				}
+				t.Cadence = kit.Ptr(uint8(u))
+			}
+			targetCharData = ""
+		case xml.EndElement:
+			if elem == se.End() {
+				return nil
+			}
+		}
+	}
+}
+
+func (t *Trackpoint) unmarshalPosition(dec *xml.Decoder, se xml.StartElement) error {
+	var targetCharData string
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch elem := token.(type) {
+		case xml.StartElement:
+			targetCharData = elem.Name.Local
+		case xml.CharData:
+			switch targetCharData {
+			case "LatitudeDegrees":
+				f, err := strconv.ParseFloat(string(elem), 64)
+				if err != nil {
+					return err
+				}
+				t.LatitudeDeg = kit.Ptr(f)
+			case "LongitudeDegrees":
+				f, err := strconv.ParseFloat(string(elem), 64)
+				if err != nil {
+					return err
+				}
+				t.LongitudeDeg = kit.Ptr(f)
+			}
+			targetCharData = ""
+		case xml.EndElement:
+			if elem == se.End() {
+				return nil
+			}
+		}
+	}
+}
+
+func (t *Trackpoint) unmarshalHeartRateBpm(dec *xml.Decoder, se xml.StartElement) error {
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch elem := token.(type) {
+		case xml.CharData:
+			u, err := strconv.ParseUint(string(elem), 10, 8)
+			if err != nil {
+				return err
+			}
+			t.HeartRateBpm = kit.Ptr(uint8(u))
+		case xml.EndElement:
+			if elem == se.End() {
+				return nil
+			}
+		}
+	}
+}
+
+// unmarshalExtensions decodes the children of <Extensions>, dispatching "TPX" to unmarshalTPX.
+func (t *Trackpoint) unmarshalExtensions(dec *xml.Decoder, se xml.StartElement) error {
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch elem := token.(type) {
+		case xml.StartElement:
+			switch elem.Name.Local {
+			case "TPX":
+				if err := t.unmarshalTPX(dec, elem); err != nil {
+					return err
+				}
+			default:
+				if err := dec.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			if elem == se.End() {
+				return nil
+			}
+		}
+	}
+}
+
+// unmarshalTPX decodes the ActivityExtension TPX element: Speed/Watts, and a nested StrengthTraining
+// extension, whose Sets are converted into t.setRecords so they survive round-trip parsing.
+func (t *Trackpoint) unmarshalTPX(dec *xml.Decoder, se xml.StartElement) error {
+	var targetCharData string
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch elem := token.(type) {
+		case xml.StartElement:
+			switch elem.Name.Local {
+			case "StrengthTraining":
+				var ext StrengthTrainingExtension
+				if err := ext.UnmarshalXML(dec, elem); err != nil {
+					return err
+				}
+				t.setRecords = append(t.setRecords, ext.ToSetRecords(t.Time)...)
+			default:
+				targetCharData = elem.Name.Local
+			}
+		case xml.CharData:
+			switch targetCharData {
+			case "Speed":
+				f, err := strconv.ParseFloat(string(elem), 64)
+				if err != nil {
+					return err
+				}
+				t.Speed = kit.Ptr(f)
+			case "Watts":
+				u, err := strconv.ParseUint(string(elem), 10, 16)
+				if err != nil {
+					return err
+				}
+				t.Watts = kit.Ptr(uint16(u))
+			}
+			targetCharData = ""
+		case xml.EndElement:
+			if elem == se.End() {
+				return nil
+			}
+		}
+	}
+}
+
+// ToRecord converts this Trackpoint into an activity.Record, carrying over whichever fields were present;
+// fields with no corresponding element in the source XML are left nil.
+func (t *Trackpoint) ToRecord() *activity.Record {
+	rec := &activity.Record{
+		Timestamp:    t.Time,
+		PositionLat:  t.LatitudeDeg,
+		PositionLong: t.LongitudeDeg,
+		Altitude:     t.AltitudeM,
+		Distance:     t.DistanceM,
+		Speed:        t.Speed,
+	}
+	if t.HeartRateBpm != nil {
+		rec.HeartRate = kit.Ptr(uint8(*t.HeartRateBpm))
+	}
+	if t.Cadence != nil {
+		rec.Cadence = kit.Ptr(uint8(*t.Cadence))
+	}
+	if t.Watts != nil {
+		rec.Power = kit.Ptr(uint16(*t.Watts))
+	}
+	return rec
+}
+
+// ToSetRecords returns the activity.SetRecord values carried by this Trackpoint's StrengthTraining
+// extension, if any, so a mixed GPS+strength session (see activity/strength.go) survives round-trip
+// parsing alongside its regular Record data.
+func (t *Trackpoint) ToSetRecords() []*activity.SetRecord {
+	return t.setRecords
+}
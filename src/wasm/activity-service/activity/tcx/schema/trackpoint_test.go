@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestTrackpoint_UnmarshalXML(t *testing.T) {
+	data := `<Trackpoint>
+		<Time>2024-01-01T12:00:00Z</Time>
+		<Position>
+			<LatitudeDegrees>37.7749</LatitudeDegrees>
+			<LongitudeDegrees>-122.4194</LongitudeDegrees>
+		</Position>
+		<AltitudeMeters>15.5</AltitudeMeters>
+		<DistanceMeters>120.3</DistanceMeters>
+		<HeartRateBpm><Value>142</Value></HeartRateBpm>
+		<Cadence>88</Cadence>
+		<Extensions>
+			<TPX xmlns="http://www.garmin.com/xmlschemas/ActivityExtension/v2">
+				<Speed>2.8</Speed>
+				<Watts>210</Watts>
+			</TPX>
+		</Extensions>
+	</Trackpoint>`
+
+	var tp Trackpoint
+	if err := xml.Unmarshal([]byte(data), &tp); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	wantTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	if !tp.Time.Equal(wantTime) {
+		t.Errorf("Time = %v, want %v", tp.Time, wantTime)
+	}
+
+	rec := tp.ToRecord()
+	if !rec.Timestamp.Equal(wantTime) {
+		t.Errorf("ToRecord().Timestamp = %v, want %v", rec.Timestamp, wantTime)
+	}
+	if rec.PositionLat == nil || *rec.PositionLat != 37.7749 {
+		t.Errorf("ToRecord().PositionLat = %v, want 37.7749", rec.PositionLat)
+	}
+	if rec.PositionLong == nil || *rec.PositionLong != -122.4194 {
+		t.Errorf("ToRecord().PositionLong = %v, want -122.4194", rec.PositionLong)
+	}
+	if rec.Altitude == nil || *rec.Altitude != 15.5 {
+		t.Errorf("ToRecord().Altitude = %v, want 15.5", rec.Altitude)
+	}
+	if rec.Distance == nil || *rec.Distance != 120.3 {
+		t.Errorf("ToRecord().Distance = %v, want 120.3", rec.Distance)
+	}
+	if rec.HeartRate == nil || *rec.HeartRate != 142 {
+		t.Errorf("ToRecord().HeartRate = %v, want 142", rec.HeartRate)
+	}
+	if rec.Cadence == nil || *rec.Cadence != 88 {
+		t.Errorf("ToRecord().Cadence = %v, want 88", rec.Cadence)
+	}
+	if rec.Speed == nil || *rec.Speed != 2.8 {
+		t.Errorf("ToRecord().Speed = %v, want 2.8", rec.Speed)
+	}
+	if rec.Power == nil || *rec.Power != 210 {
+		t.Errorf("ToRecord().Power = %v, want 210", rec.Power)
+	}
+
+	if got := tp.ToSetRecords(); got != nil {
+		t.Errorf("ToSetRecords() = %v, want nil (no StrengthTraining extension)", got)
+	}
+}
+
+// TestTrackpoint_UnmarshalXML_MixedStrengthTraining covers a HIIT-style session with both GPS fields and
+// a StrengthTraining extension on the same Trackpoint (see activity/strength.go), so a Trackpoint carries
+// both an activity.Record and its activity.SetRecords through round-trip parsing.
+func TestTrackpoint_UnmarshalXML_MixedStrengthTraining(t *testing.T) {
+	data := `<Trackpoint>
+		<Time>2024-01-01T12:00:00Z</Time>
+		<Position>
+			<LatitudeDegrees>37.7749</LatitudeDegrees>
+			<LongitudeDegrees>-122.4194</LongitudeDegrees>
+		</Position>
+		<HeartRateBpm><Value>150</Value></HeartRateBpm>
+		<Extensions>
+			<TPX xmlns="http://www.garmin.com/xmlschemas/ActivityExtension/v2">
+				<StrengthTraining>
+					<Sets>
+						<Set>
+							<Exercise>Bench Press</Exercise>
+							<Repetitions>10</Repetitions>
+							<Weight>60</Weight>
+							<RestSeconds>90</RestSeconds>
+						</Set>
+					</Sets>
+				</StrengthTraining>
+			</TPX>
+		</Extensions>
+	</Trackpoint>`
+
+	var tp Trackpoint
+	if err := xml.Unmarshal([]byte(data), &tp); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	wantTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	rec := tp.ToRecord()
+	if rec.HeartRate == nil || *rec.HeartRate != 150 {
+		t.Errorf("ToRecord().HeartRate = %v, want 150", rec.HeartRate)
+	}
+	if rec.PositionLat == nil || *rec.PositionLat != 37.7749 {
+		t.Errorf("ToRecord().PositionLat = %v, want 37.7749", rec.PositionLat)
+	}
+
+	sets := tp.ToSetRecords()
+	if len(sets) != 1 {
+		t.Fatalf("len(ToSetRecords()) = %d, want 1", len(sets))
+	}
+	if !sets[0].Timestamp.Equal(wantTime) {
+		t.Errorf("ToSetRecords()[0].Timestamp = %v, want %v", sets[0].Timestamp, wantTime)
+	}
+	if sets[0].Exercise != "Bench Press" {
+		t.Errorf("ToSetRecords()[0].Exercise = %q, want %q", sets[0].Exercise, "Bench Press")
+	}
+	if sets[0].Reps == nil || *sets[0].Reps != 10 {
+		t.Errorf("ToSetRecords()[0].Reps = %v, want 10", sets[0].Reps)
+	}
+}
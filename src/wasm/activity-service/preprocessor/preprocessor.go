@@ -1,6 +1,8 @@
 package preprocessor
 
 import (
+	"time"
+
 	"github.com/muktihari/openactivity-fit/activity"
 	"github.com/muktihari/openactivity-fit/geomath"
 	"github.com/muktihari/openactivity-fit/kit"
@@ -12,13 +14,13 @@ type Preprocessor struct {
 }
 
 type options struct {
-	smoothingElevDistance  float64 // in meters
+	elevationSmoother      Smoother
 	calculateGradeDistance float64 // in meters
 }
 
 func defaultOptions() *options {
 	return &options{
-		smoothingElevDistance:  30,
+		elevationSmoother:      NewSMASmoother(30),
 		calculateGradeDistance: 100,
 	}
 }
@@ -29,10 +31,25 @@ type fnApply func(*options)
 
 func (f fnApply) apply(o *options) { f(o) }
 
+// WithSmoothingElevationDistance sets the trailing distance (in meters) the default SMA elevation
+// smoother averages over. It has no effect if WithElevationSmoother has set a non-SMA smoother.
 func WithSmoothingElevationDistance(d float64) Option {
 	return fnApply(func(o *options) {
-		if d > 0 {
-			o.smoothingElevDistance = d
+		if d <= 0 {
+			return
+		}
+		if sma, ok := o.elevationSmoother.(*SMASmoother); ok {
+			sma.distance = d
+		}
+	})
+}
+
+// WithElevationSmoother overrides the Smoother used by SmoothingElev, e.g. to use SGSmoother or
+// KalmanSmoother instead of the default SMASmoother.
+func WithElevationSmoother(s Smoother) Option {
+	return fnApply(func(o *options) {
+		if s != nil {
+			o.elevationSmoother = s
 		}
 	})
 }
@@ -74,22 +91,7 @@ func (p *Preprocessor) AggregateByTimestamp(records []*activity.Record) []*activ
 		}
 
 		for j := range candidates {
-			can := candidates[j]
-
-			if rec.PositionLat == nil {
-				rec.PositionLat = can.PositionLat
-			}
-			if rec.PositionLong == nil {
-				rec.PositionLong = can.PositionLong
-			}
-
-			rec.Altitude = avg(rec.Altitude, can.Altitude)
-			rec.Cadence = avg(rec.Cadence, can.Cadence)
-			rec.Speed = avg(rec.Speed, can.Speed)
-			rec.Distance = avg(rec.Distance, can.Distance)
-			rec.HeartRate = avg(rec.HeartRate, can.HeartRate)
-			rec.Power = avg(rec.Power, can.Power)
-			rec.Temperature = avg(rec.Temperature, can.Temperature)
+			mergeRecord(rec, candidates[j])
 		}
 
 		newRecords = append(newRecords, rec)
@@ -98,8 +100,33 @@ func (p *Preprocessor) AggregateByTimestamp(records []*activity.Record) []*activ
 	return newRecords
 }
 
+// mergeRecord merges src into dst in place, filling in dst's nil position fields from src and averaging
+// the rest, used to coalesce records sharing a single timestamp.
+func mergeRecord(dst, src *activity.Record) {
+	if dst.PositionLat == nil {
+		dst.PositionLat = src.PositionLat
+	}
+	if dst.PositionLong == nil {
+		dst.PositionLong = src.PositionLong
+	}
+
+	dst.Altitude = avg(dst.Altitude, src.Altitude)
+	dst.Cadence = avg(dst.Cadence, src.Cadence)
+	dst.Speed = avg(dst.Speed, src.Speed)
+	dst.Distance = avg(dst.Distance, src.Distance)
+	dst.HeartRate = avg(dst.HeartRate, src.HeartRate)
+	dst.Power = avg(dst.Power, src.Power)
+	dst.Temperature = avg(dst.Temperature, src.Temperature)
+}
+
 // CalculateDistanceAndSpeed calculates distance from latitude and longitude and speed when those values are missing.
-func (p *Preprocessor) CalculateDistanceAndSpeed(records []*activity.Record) {
+// It is a no-op for non-locomotive sports (see activity.IsNonLocomotive), e.g. weight training, since those
+// records carry no meaningful GPS coordinates to derive distance or speed from.
+func (p *Preprocessor) CalculateDistanceAndSpeed(sport string, records []*activity.Record) {
+	if activity.IsNonLocomotive(sport) {
+		return
+	}
+
 	for i := 1; i < len(records); i++ {
 		rec := records[i]
 		prev := records[i-1]
@@ -139,36 +166,26 @@ func (p *Preprocessor) CalculateDistanceAndSpeed(records []*activity.Record) {
 	}
 }
 
-// SmoothingElev smoothing elevation values using simple moving average.
-func (p *Preprocessor) SmoothingElev(records []*activity.Record) {
-	for i := range records {
-		rec := records[i]
-		if rec.Distance == nil || rec.Altitude == nil {
-			continue
-		}
-
-		var sum, counter float64
-		for j := i; j >= 0; j-- {
-			prev := records[j]
-			if prev.Distance == nil || prev.Altitude == nil {
-				continue
-			}
-
-			d := *rec.Distance - *prev.Distance
-			if d > p.options.smoothingElevDistance {
-				break
-			}
-			sum += *prev.Altitude
-			counter++
-		}
-
-		altitude := sum / counter
-		rec.Altitude = &altitude
+// SmoothingElev smooths elevation values using the configured Smoother (SMA by default, see
+// WithElevationSmoother).
+// It is a no-op for non-locomotive sports (see activity.IsNonLocomotive) since those records have no
+// distance to smooth over.
+func (p *Preprocessor) SmoothingElev(sport string, records []*activity.Record) {
+	if activity.IsNonLocomotive(sport) {
+		return
 	}
+
+	p.options.elevationSmoother.Smooth(records)
 }
 
 // CalculateGrade calculates grade percentage.
-func (p *Preprocessor) CalculateGrade(records []*activity.Record) {
+// It is a no-op for non-locomotive sports (see activity.IsNonLocomotive) since those records have no
+// distance/elevation profile to grade.
+func (p *Preprocessor) CalculateGrade(sport string, records []*activity.Record) {
+	if activity.IsNonLocomotive(sport) {
+		return
+	}
+
 	for i := range records {
 		rec := records[i]
 		if rec.Distance == nil || rec.Altitude == nil {
@@ -200,8 +217,14 @@ func (p *Preprocessor) CalculateGrade(records []*activity.Record) {
 	}
 }
 
-// CalculatePace calculates pace time/distance (distance in km)
+// CalculatePace calculates pace time/distance (distance in km).
+// It is a no-op for non-locomotive sports (see activity.IsNonLocomotive) since those records have no
+// meaningful distance to pace against.
 func (p *Preprocessor) CalculatePace(sport string, records []*activity.Record) {
+	if activity.IsNonLocomotive(sport) {
+		return
+	}
+
 	for i := 1; i < len(records); i++ {
 		rec := records[i]
 		prev := records[i-1]
@@ -230,6 +253,97 @@ func (p *Preprocessor) CalculatePace(sport string, records []*activity.Record) {
 	}
 }
 
+// AggregateSets groups set records belonging to the same exercise and SetIndex, merging any that were
+// split across multiple records by the source device, similar in spirit to AggregateByTimestamp.
+func (p *Preprocessor) AggregateSets(records []*activity.SetRecord) []*activity.SetRecord {
+	newRecords := make([]*activity.SetRecord, 0, len(records))
+
+	for i := 0; i < len(records); i++ {
+		rec := records[i]
+
+		candidates := make([]*activity.SetRecord, 0)
+		j := i + 1
+		for ; j < len(records); j++ {
+			next := records[j]
+			if rec.Exercise != next.Exercise || rec.SetIndex == nil || next.SetIndex == nil ||
+				*rec.SetIndex != *next.SetIndex {
+				break
+			}
+			candidates = append(candidates, next)
+		}
+		i = j - 1
+
+		for j := range candidates {
+			can := candidates[j]
+			rec.Reps = avg(rec.Reps, can.Reps)
+			rec.Weight = avg(rec.Weight, can.Weight)
+			rec.RestSeconds = avg(rec.RestSeconds, can.RestSeconds)
+		}
+
+		newRecords = append(newRecords, rec)
+	}
+
+	return newRecords
+}
+
+// CalculateVolume calculates total training volume (Σ reps × weight) across all sets.
+func (p *Preprocessor) CalculateVolume(records []*activity.SetRecord) float64 {
+	var volume float64
+	for i := range records {
+		rec := records[i]
+		if rec.Reps == nil || rec.Weight == nil {
+			continue
+		}
+		volume += float64(*rec.Reps) * *rec.Weight
+	}
+	return volume
+}
+
+// DetectRestPeriods returns the timestamps at which a rest period of at least threshold seconds was
+// taken between two consecutive sets.
+func (p *Preprocessor) DetectRestPeriods(records []*activity.SetRecord, threshold float64) []time.Time {
+	rests := make([]time.Time, 0)
+	for i := 1; i < len(records); i++ {
+		rec := records[i]
+		prev := records[i-1]
+
+		elapsed := rec.Timestamp.Sub(prev.Timestamp).Seconds()
+		if elapsed >= threshold {
+			rests = append(rests, rec.Timestamp)
+		}
+	}
+	return rests
+}
+
+// CalculateWorkDensity calculates the ratio of working seconds (time actually spent performing sets,
+// i.e. excluding rest) to total elapsed seconds across records, for non-locomotive sports where pace and
+// speed don't apply.
+func (p *Preprocessor) CalculateWorkDensity(sport string, records []*activity.SetRecord) float64 {
+	if !activity.IsNonLocomotive(sport) || len(records) < 2 {
+		return 0
+	}
+
+	total := records[len(records)-1].Timestamp.Sub(records[0].Timestamp).Seconds()
+	if total <= 0 {
+		return 0
+	}
+
+	var resting float64
+	for i := 1; i < len(records); i++ {
+		rec := records[i]
+		if rec.RestSeconds != nil {
+			resting += *rec.RestSeconds
+		}
+	}
+
+	working := total - resting
+	if working < 0 {
+		working = 0
+	}
+
+	return working / total
+}
+
 // avg returns average of two non-nil values. Otherwise, return any non-nil value if possible.
 func avg[T constraints.Integer | constraints.Float](a, b *T) *T {
 	if a == nil {
@@ -0,0 +1,113 @@
+package preprocessor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/muktihari/openactivity-fit/activity"
+	"github.com/muktihari/openactivity-fit/kit"
+)
+
+func setRecord(ts time.Time, exercise string, setIndex uint16, reps uint16, weight, restSeconds float64) *activity.SetRecord {
+	return &activity.SetRecord{
+		Timestamp:   ts,
+		Exercise:    exercise,
+		Reps:        kit.Ptr(reps),
+		Weight:      kit.Ptr(weight),
+		SetIndex:    kit.Ptr(setIndex),
+		RestSeconds: kit.Ptr(restSeconds),
+	}
+}
+
+func TestPreprocessor_AggregateSets(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	records := []*activity.SetRecord{
+		setRecord(base, "bench_press", 1, 10, 60, 0),
+		setRecord(base, "bench_press", 2, 8, 60, 90),
+		setRecord(base, "squat", 1, 5, 100, 0),
+		// Trailing run of the same exercise/SetIndex that extends to the end of the slice.
+		setRecord(base, "squat", 2, 4, 100, 120),
+		setRecord(base, "squat", 2, 6, 100, 120),
+	}
+
+	p := New()
+	got := p.AggregateSets(records)
+
+	// bench_press set 1, bench_press set 2, squat set 1, and squat set 2 (merged from the trailing pair).
+	if len(got) != 4 {
+		t.Fatalf("len(got) = %d, want 4", len(got))
+	}
+
+	if got[0].Exercise != "bench_press" || *got[0].SetIndex != 1 {
+		t.Errorf("got[0] = %+v, want bench_press set 1", got[0])
+	}
+	if got[1].Exercise != "bench_press" || *got[1].SetIndex != 2 {
+		t.Errorf("got[1] = %+v, want bench_press set 2", got[1])
+	}
+	if got[2].Exercise != "squat" || *got[2].SetIndex != 1 {
+		t.Errorf("got[2] = %+v, want squat set 1", got[2])
+	}
+
+	last := got[3]
+	if last.Exercise != "squat" || *last.SetIndex != 2 {
+		t.Fatalf("got[3] = %+v, want squat set 2", last)
+	}
+	if *last.Reps != 5 {
+		t.Errorf("got[3].Reps = %d, want 5 (avg of 4 and 6)", *last.Reps)
+	}
+}
+
+func TestPreprocessor_CalculateVolume(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []*activity.SetRecord{
+		setRecord(base, "bench_press", 1, 10, 60, 0),
+		setRecord(base, "squat", 1, 5, 100, 0),
+		{Timestamp: base, Exercise: "plank"}, // no reps/weight, should be skipped
+	}
+
+	p := New()
+	got := p.CalculateVolume(records)
+	want := 10*60.0 + 5*100.0
+	if got != want {
+		t.Errorf("CalculateVolume() = %v, want %v", got, want)
+	}
+}
+
+func TestPreprocessor_DetectRestPeriods(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []*activity.SetRecord{
+		setRecord(base, "bench_press", 1, 10, 60, 0),
+		setRecord(base.Add(30*time.Second), "bench_press", 2, 8, 60, 0),
+		setRecord(base.Add(150*time.Second), "squat", 1, 5, 100, 0),
+	}
+
+	p := New()
+	rests := p.DetectRestPeriods(records, 60)
+
+	if len(rests) != 1 {
+		t.Fatalf("len(rests) = %d, want 1", len(rests))
+	}
+	if !rests[0].Equal(records[2].Timestamp) {
+		t.Errorf("rests[0] = %v, want %v", rests[0], records[2].Timestamp)
+	}
+}
+
+func TestPreprocessor_CalculateWorkDensity(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []*activity.SetRecord{
+		setRecord(base, "bench_press", 1, 10, 60, 0),
+		setRecord(base.Add(100*time.Second), "bench_press", 2, 8, 60, 40),
+	}
+
+	p := New()
+	got := p.CalculateWorkDensity("weight_training", records)
+	want := (100.0 - 40.0) / 100.0
+	if got != want {
+		t.Errorf("CalculateWorkDensity() = %v, want %v", got, want)
+	}
+
+	if got := p.CalculateWorkDensity("running", records); got != 0 {
+		t.Errorf("CalculateWorkDensity() for locomotive sport = %v, want 0", got)
+	}
+}
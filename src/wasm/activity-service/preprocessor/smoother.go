@@ -0,0 +1,98 @@
+package preprocessor
+
+import "github.com/muktihari/openactivity-fit/activity"
+
+// Smoother smooths the Altitude values of records in place.
+type Smoother interface {
+	Smooth(records []*activity.Record)
+}
+
+// tailSmoother is implemented by Smoothers whose output depends on carrying state across repeated calls
+// and/or on knowing whether a slice edge is a true activity boundary (SGSmoother, KalmanSmoother).
+// StreamingPreprocessor calls SmoothTail instead of Smooth when the configured Smoother implements this.
+type tailSmoother interface {
+	Smoother
+
+	// SmoothTail smooths records[from:], using records[:from] as read-only backward context. atStart and
+	// atEnd report whether records[0] and records[len(records)-1] are the true start/end of the activity,
+	// as opposed to merely the edge of the currently resident streaming window.
+	SmoothTail(records []*activity.Record, from int, atStart, atEnd bool)
+}
+
+// streamCloner is implemented by Smoothers that carry mutable filter state across Smooth/SmoothTail
+// calls (KalmanSmoother). StreamingPreprocessor clones these so each stream gets its own isolated state.
+type streamCloner interface{ cloneForStream() Smoother }
+
+// forwardMargin returns how many additional records a tailSmoother needs resident ahead of a record
+// before that record can be finalized, beyond whatever CalculateGrade's distance gate already provides.
+// SGSmoother needs its half window in records regardless of distance; other smoothers return 0.
+func forwardMargin(s Smoother) int {
+	if sg, ok := s.(*SGSmoother); ok {
+		return sg.N / 2
+	}
+	return 0
+}
+
+// backwardMargin returns the minimum number of already-finalized records a tailSmoother needs retained as
+// context, beyond whatever smoothingWindowMeters's distance heuristic already retains. SGSmoother needs
+// its half window in records regardless of distance; KalmanSmoother needs its immediately preceding record.
+func backwardMargin(s Smoother) int {
+	switch sm := s.(type) {
+	case *SGSmoother:
+		return sm.N / 2
+	case *KalmanSmoother:
+		return 1
+	}
+	return 0
+}
+
+// SMASmoother smooths altitude using a one-sided simple moving average over distance. It is the
+// smoother Preprocessor uses by default, as it always has, but it lags on climbs and does not reject
+// isolated GPS altitude spikes; SGSmoother or KalmanSmoother are better suited for those cases.
+type SMASmoother struct{ distance float64 }
+
+// NewSMASmoother creates a SMASmoother that averages altitude over the trailing distance meters.
+func NewSMASmoother(distance float64) *SMASmoother {
+	if distance <= 0 {
+		distance = 30
+	}
+	return &SMASmoother{distance: distance}
+}
+
+// smoothingWindowMeters returns the trailing distance (in meters) a Smoother needs buffered before its
+// output for a given record is considered final. SMASmoother reports its own configured distance; other
+// smoothers fall back to the same default SMASmoother uses so callers that need a window size (e.g.
+// StreamingPreprocessor) always get a sane one.
+func smoothingWindowMeters(s Smoother) float64 {
+	if sma, ok := s.(*SMASmoother); ok {
+		return sma.distance
+	}
+	return 30
+}
+
+func (s *SMASmoother) Smooth(records []*activity.Record) {
+	for i := range records {
+		rec := records[i]
+		if rec.Distance == nil || rec.Altitude == nil {
+			continue
+		}
+
+		var sum, counter float64
+		for j := i; j >= 0; j-- {
+			prev := records[j]
+			if prev.Distance == nil || prev.Altitude == nil {
+				continue
+			}
+
+			d := *rec.Distance - *prev.Distance
+			if d > s.distance {
+				break
+			}
+			sum += *prev.Altitude
+			counter++
+		}
+
+		altitude := sum / counter
+		rec.Altitude = &altitude
+	}
+}
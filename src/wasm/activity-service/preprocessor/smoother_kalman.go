@@ -0,0 +1,86 @@
+package preprocessor
+
+import "github.com/muktihari/openactivity-fit/activity"
+
+// KalmanSmoother smooths altitude with a scalar Kalman filter, treating the true altitude as a random
+// walk (x_k = x_k-1 + v_k·Δt) whose process variance grows with the horizontal distance covered since
+// the previous record.
+type KalmanSmoother struct {
+	ProcessVarianceFactor float64 // Q per meter of horizontal distance travelled
+	MeasurementVariance   float64 // R, in meters^2; the same constant is used for every record
+
+	x, p        float64 // posterior altitude estimate and its variance, carried across SmoothTail calls
+	initialized bool
+}
+
+// NewKalmanSmoother creates a KalmanSmoother. processVarianceFactor scales Q by the distance travelled
+// between records; measurementVariance (R) is the assumed variance of the raw altitude reading itself.
+func NewKalmanSmoother(processVarianceFactor, measurementVariance float64) *KalmanSmoother {
+	if processVarianceFactor <= 0 {
+		processVarianceFactor = 0.01
+	}
+	if measurementVariance <= 0 {
+		measurementVariance = 4 // ~2m stddev, typical consumer GPS altitude accuracy
+	}
+	return &KalmanSmoother{
+		ProcessVarianceFactor: processVarianceFactor,
+		MeasurementVariance:   measurementVariance,
+	}
+}
+
+func (k *KalmanSmoother) Smooth(records []*activity.Record) {
+	k.x, k.p, k.initialized = 0, 0, false
+	k.smooth(records, 0)
+}
+
+// SmoothTail smooths records[from:], carrying filter state across repeated calls instead of resetting it
+// every call. The filter is (re)initialized only when atStart reports records[0] is the true activity
+// start and from is 0; atEnd is unused, KalmanSmoother has no trailing boundary.
+func (k *KalmanSmoother) SmoothTail(records []*activity.Record, from int, atStart, atEnd bool) {
+	if atStart && from == 0 {
+		k.x, k.p, k.initialized = 0, 0, false
+	}
+	k.smooth(records, from)
+}
+
+func (k *KalmanSmoother) smooth(records []*activity.Record, from int) {
+	for i := from; i < len(records); i++ {
+		rec := records[i]
+		if rec.Altitude == nil {
+			continue
+		}
+
+		if !k.initialized {
+			k.x, k.p = *rec.Altitude, k.MeasurementVariance
+			k.initialized = true
+			continue
+		}
+
+		var distanceStep float64
+		if i > 0 {
+			if prev := records[i-1]; rec.Distance != nil && prev.Distance != nil {
+				distanceStep = *rec.Distance - *prev.Distance
+			}
+		}
+
+		// Predict
+		q := k.ProcessVarianceFactor * distanceStep
+		k.p += q
+
+		// Update
+		gain := k.p / (k.p + k.MeasurementVariance)
+		k.x += gain * (*rec.Altitude - k.x)
+		k.p *= 1 - gain
+
+		altitude := k.x
+		rec.Altitude = &altitude
+	}
+}
+
+// cloneForStream returns a copy of k with filter state reset, so one stream's state can't leak into
+// another's when the same KalmanSmoother instance is reused across streams.
+func (k *KalmanSmoother) cloneForStream() Smoother {
+	clone := *k
+	clone.x, clone.p, clone.initialized = 0, 0, false
+	return &clone
+}
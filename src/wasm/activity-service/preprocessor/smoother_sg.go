@@ -0,0 +1,225 @@
+package preprocessor
+
+import (
+	"fmt"
+
+	"github.com/muktihari/openactivity-fit/activity"
+)
+
+// SGSmoother smooths altitude using a centered Savitzky–Golay filter: a polynomial of Order is
+// least-squares fit to the N samples surrounding each point and the fitted value at the center is
+// written back.
+type SGSmoother struct {
+	N     int // window size, must be odd and >= 3
+	Order int // polynomial order, must be <= N-1
+
+	coeffs []float64 // convolution coefficients for the full window, precomputed once
+}
+
+// NewSGSmoother creates a SGSmoother for window size n (must be odd, >= 3) and polynomial order (must be
+// in [0, n-1]).
+func NewSGSmoother(n, order int) (*SGSmoother, error) {
+	if n < 3 || n%2 == 0 {
+		return nil, fmt.Errorf("preprocessor: Savitzky-Golay window N must be odd and >= 3, got %d", n)
+	}
+	if order < 0 || order > n-1 {
+		return nil, fmt.Errorf("preprocessor: Savitzky-Golay order must be in [0, %d], got %d", n-1, order)
+	}
+
+	coeffs, err := savitzkyGolayCoefficients(n, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SGSmoother{N: n, Order: order, coeffs: coeffs}, nil
+}
+
+// Smooth convolves the SG coefficients over the altitude series. Near the start/end of records, where
+// the full window doesn't fit, the window is shrunk symmetrically around the point (and its
+// coefficients recomputed) rather than mirrored, so no synthetic altitude values are invented.
+func (s *SGSmoother) Smooth(records []*activity.Record) {
+	s.smooth(records, 0, true, true)
+}
+
+// SmoothTail smooths records[from:], like Smooth, but atStart/atEnd report whether records[0] and
+// records[len(records)-1] are the true start/end of the activity. When an edge is not a true boundary,
+// SGSmoother leaves samples whose window doesn't fully fit unfinalized instead of shrinking the window.
+func (s *SGSmoother) SmoothTail(records []*activity.Record, from int, atStart, atEnd bool) {
+	s.smooth(records, from, atStart, atEnd)
+}
+
+func (s *SGSmoother) smooth(records []*activity.Record, from int, atStart, atEnd bool) {
+	half := s.N / 2
+
+	// Computed into a separate slice and copied back after the loop so each output only ever convolves
+	// over the original altitude series, not values this same call has already smoothed.
+	out := make([]*float64, len(records))
+
+	for i := from; i < len(records); i++ {
+		if records[i].Altitude == nil {
+			continue
+		}
+
+		lo, hi := i-half, i+half
+		trueLo, trueHi := true, true
+		if lo < 0 {
+			lo = 0
+			trueLo = atStart
+		}
+		if hi > len(records)-1 {
+			hi = len(records) - 1
+			trueHi = atEnd
+		}
+		if !trueLo || !trueHi {
+			continue // window doesn't fully fit and this isn't a true boundary; wait for more data
+		}
+		w := minInt(i-lo, hi-i)
+		lo, hi = i-w, i+w
+		window := hi - lo + 1
+
+		coeffs := s.coeffs
+		if window != s.N {
+			if window < 3 {
+				continue
+			}
+			order := s.Order
+			if order > window-1 {
+				order = window - 1
+			}
+			var err error
+			coeffs, err = savitzkyGolayCoefficients(window, order)
+			if err != nil {
+				continue
+			}
+		}
+
+		var sum float64
+		ok := true
+		for j := 0; j < window; j++ {
+			alt := records[lo+j].Altitude
+			if alt == nil {
+				ok = false
+				break
+			}
+			sum += coeffs[j] * (*alt)
+		}
+		if !ok {
+			continue
+		}
+
+		altitude := sum
+		out[i] = &altitude
+	}
+
+	for i := from; i < len(records); i++ {
+		if out[i] != nil {
+			records[i].Altitude = out[i]
+		}
+	}
+}
+
+// savitzkyGolayCoefficients computes the convolution coefficients that estimate the centered value of a
+// degree-order polynomial least-squares fit over a window of n samples, i.e. the first row of
+// (AᵀA)⁻¹Aᵀ where A[i][j] = (i - n/2)^j.
+func savitzkyGolayCoefficients(n, order int) ([]float64, error) {
+	half := n / 2
+
+	a := make([][]float64, n)
+	for i := 0; i < n; i++ {
+		a[i] = make([]float64, order+1)
+		x := float64(i - half)
+		xp := 1.0
+		for j := 0; j <= order; j++ {
+			a[i][j] = xp
+			xp *= x
+		}
+	}
+
+	ata := make([][]float64, order+1)
+	for j := 0; j <= order; j++ {
+		ata[j] = make([]float64, order+1)
+		for k := 0; k <= order; k++ {
+			var sum float64
+			for i := 0; i < n; i++ {
+				sum += a[i][j] * a[i][k]
+			}
+			ata[j][k] = sum
+		}
+	}
+
+	ataInv, err := invertSquareMatrix(ata)
+	if err != nil {
+		return nil, fmt.Errorf("preprocessor: failed computing Savitzky-Golay coefficients: %w", err)
+	}
+
+	coeffs := make([]float64, n)
+	for i := 0; i < n; i++ {
+		var sum float64
+		for j := 0; j <= order; j++ {
+			sum += ataInv[0][j] * a[i][j]
+		}
+		coeffs[i] = sum
+	}
+
+	return coeffs, nil
+}
+
+// invertSquareMatrix inverts m using Gauss-Jordan elimination with partial pivoting. m is not modified.
+func invertSquareMatrix(m [][]float64) ([][]float64, error) {
+	n := len(m)
+
+	aug := make([][]float64, n)
+	for i := 0; i < n; i++ {
+		aug[i] = make([]float64, 2*n)
+		copy(aug[i], m[i])
+		aug[i][n+i] = 1
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if abs(aug[row][col]) > abs(aug[pivot][col]) {
+				pivot = row
+			}
+		}
+		if abs(aug[pivot][col]) < 1e-12 {
+			return nil, fmt.Errorf("singular matrix")
+		}
+		aug[col], aug[pivot] = aug[pivot], aug[col]
+
+		pivotVal := aug[col][col]
+		for k := 0; k < 2*n; k++ {
+			aug[col][k] /= pivotVal
+		}
+
+		for row := 0; row < n; row++ {
+			if row == col {
+				continue
+			}
+			factor := aug[row][col]
+			for k := 0; k < 2*n; k++ {
+				aug[row][k] -= factor * aug[col][k]
+			}
+		}
+	}
+
+	inv := make([][]float64, n)
+	for i := 0; i < n; i++ {
+		inv[i] = aug[i][n:]
+	}
+	return inv, nil
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,231 @@
+package preprocessor
+
+import "github.com/muktihari/openactivity-fit/activity"
+
+// StreamingPreprocessor runs the same computations as Preprocessor (AggregateByTimestamp,
+// CalculateDistanceAndSpeed, SmoothingElev, CalculateGrade, CalculatePace) over records arriving one at a
+// time, e.g. from a live telemetry feed, instead of requiring a full activity to be buffered upfront.
+//
+// Push returns records in order, each exactly once, as soon as enough forward distance has accumulated
+// past it for CalculateGrade/SmoothingElev to have reached their final value; Flush returns whatever is
+// left in the buffer as its final values. For non-locomotive sports (see activity.IsNonLocomotive) there
+// is no Distance to gate on, so records are emitted as soon as a later timestamp confirms them complete.
+type StreamingPreprocessor struct {
+	p     *Preprocessor
+	sport string
+
+	buf        []*activity.Record // context (already emitted, kept for backward smoothing) + pending
+	contextLen int                // leading records in buf already emitted, kept only for context
+
+	frontEvicted bool // true once buf[0] has ever stopped being the true first record of the activity
+}
+
+// NewStreaming creates a StreamingPreprocessor for the given sport (see activity.IsNonLocomotive),
+// configured with the same Options as New. A Smoother passed via WithElevationSmoother that carries state
+// across calls (see streamCloner) is cloned so this stream gets its own isolated state.
+func NewStreaming(sport string, opts ...Option) *StreamingPreprocessor {
+	p := New(opts...)
+	if c, ok := p.options.elevationSmoother.(streamCloner); ok {
+		p.options.elevationSmoother = c.cloneForStream()
+	}
+	return &StreamingPreprocessor{p: p, sport: sport}
+}
+
+// Push adds rec to the stream and returns any records that are now finalized, in order. It may return
+// nil if rec is still within the trailing window of every buffered record.
+func (s *StreamingPreprocessor) Push(rec *activity.Record) []*activity.Record {
+	s.buf = append(s.buf, rec)
+	return s.process(false)
+}
+
+// Flush finalizes and returns every record still held in the internal buffer, in order. After Flush the
+// StreamingPreprocessor is empty and ready to process a new activity.
+func (s *StreamingPreprocessor) Flush() []*activity.Record {
+	return s.process(true)
+}
+
+func (s *StreamingPreprocessor) process(flush bool) []*activity.Record {
+	pending := s.buf[s.contextLen:]
+	if len(pending) == 0 {
+		return nil
+	}
+
+	nonLocomotive := activity.IsNonLocomotive(s.sport)
+
+	gradeWindow := s.p.options.calculateGradeDistance
+	elevWindow := smoothingWindowMeters(s.p.options.elevationSmoother)
+	if nonLocomotive {
+		elevWindow = 0 // no Distance/Grade/elevation smoothing to wait on, see readyCountNonLocomotive
+	}
+
+	readyUpto := len(pending)
+	if !flush {
+		switch {
+		case nonLocomotive:
+			readyUpto = readyCountNonLocomotive(pending)
+		default:
+			readyUpto = readyCount(pending, gradeWindow)
+			if m := forwardMargin(s.p.options.elevationSmoother); m > 0 && readyUpto > len(pending)-m {
+				readyUpto = len(pending) - m
+				if readyUpto < 0 {
+					readyUpto = 0
+				}
+			}
+		}
+	}
+
+	pending, readyUpto = coalesceHead(pending, readyUpto)
+	s.buf = append(s.buf[:s.contextLen], pending...)
+
+	// Recompute over the whole resident buffer (context + pending) so the streaming output matches a
+	// batch run over the same records; SmoothingElev isn't called directly, see smoothElevation.
+	s.p.CalculateDistanceAndSpeed(s.sport, s.buf)
+	s.smoothElevation(!s.frontEvicted, flush)
+	s.p.CalculateGrade(s.sport, s.buf)
+	s.p.CalculatePace(s.sport, s.buf)
+
+	if flush {
+		readyUpto = len(pending)
+	}
+
+	ready := make([]*activity.Record, readyUpto)
+	copy(ready, pending[:readyUpto])
+
+	if flush {
+		s.buf, s.contextLen, s.frontEvicted = nil, 0, false
+		return ready
+	}
+
+	keepFrom := retainFrom(pending, readyUpto, elevWindow)
+	if m := backwardMargin(s.p.options.elevationSmoother); !nonLocomotive && m > 0 && keepFrom > readyUpto-m {
+		keepFrom = readyUpto - m
+		if keepFrom < 0 {
+			keepFrom = 0
+		}
+	}
+	if keepFrom > 0 {
+		s.frontEvicted = true
+	}
+	s.buf = append([]*activity.Record{}, pending[keepFrom:]...)
+	s.contextLen = readyUpto - keepFrom
+
+	return ready
+}
+
+// smoothElevation applies the configured elevation Smoother to the resident buffer. Smoothers that
+// implement tailSmoother are given only s.buf[s.contextLen:] to finalize, with s.buf[:s.contextLen] as
+// read-only backward context and atStart/atEnd reporting whether either edge is a true activity boundary
+// (see KalmanSmoother, SGSmoother). Other smoothers (SMASmoother) are recomputed from scratch every call.
+func (s *StreamingPreprocessor) smoothElevation(atStart, atEnd bool) {
+	if activity.IsNonLocomotive(s.sport) {
+		return
+	}
+
+	smoother := s.p.options.elevationSmoother
+	if ts, ok := smoother.(tailSmoother); ok {
+		ts.SmoothTail(s.buf, s.contextLen, atStart, atEnd)
+		return
+	}
+	smoother.Smooth(s.buf)
+}
+
+// readyCount returns how many records at the head of records have at least window meters of forward
+// distance accumulated past them, i.e. how many CalculateGrade has already finalized.
+func readyCount(records []*activity.Record, window float64) int {
+	last := records[len(records)-1]
+	if last.Distance == nil {
+		return 0
+	}
+
+	n := 0
+	for n < len(records) {
+		rec := records[n]
+		if rec.Distance == nil || *last.Distance-*rec.Distance < window {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// readyCountNonLocomotive returns how many records at the head of records are no longer part of the
+// trailing same-timestamp run, which is the earliest point a non-locomotive stream (no Distance to gate
+// readiness on) can tell a run is complete.
+func readyCountNonLocomotive(records []*activity.Record) int {
+	last := records[len(records)-1]
+	n := len(records) - 1
+	for n > 0 && records[n-1].Timestamp.Equal(last.Timestamp) {
+		n--
+	}
+	return n
+}
+
+// retainFrom returns the index, within records, from which buffered records must be kept after emitting
+// records[:readyUpto], so that window meters of backward context remain available to SmoothingElev for
+// whatever record becomes the new head.
+func retainFrom(records []*activity.Record, readyUpto int, window float64) int {
+	if readyUpto == 0 || readyUpto >= len(records) {
+		return readyUpto
+	}
+
+	newHead := records[readyUpto]
+	if newHead.Distance == nil {
+		return readyUpto
+	}
+
+	i := readyUpto
+	for i > 0 {
+		prev := records[i-1]
+		if prev.Distance == nil || *newHead.Distance-*prev.Distance > window {
+			break
+		}
+		i--
+	}
+	return i
+}
+
+// coalesceHead merges contiguous runs of equal-timestamp records within records[:upto] (extended as
+// needed to finish a run crossing the upto boundary) into a single record each, the streaming equivalent
+// of Preprocessor.AggregateByTimestamp. It returns the new slice and the adjusted upto index.
+func coalesceHead(records []*activity.Record, upto int) ([]*activity.Record, int) {
+	merged := make([]*activity.Record, 0, len(records))
+
+	i := 0
+	for i < upto {
+		rec := records[i]
+		j := i + 1
+		for j < len(records) && records[j].Timestamp.Equal(rec.Timestamp) {
+			mergeRecord(rec, records[j])
+			j++
+		}
+		merged = append(merged, rec)
+		i = j
+	}
+	newUpto := len(merged)
+
+	merged = append(merged, records[i:]...)
+
+	return merged, newUpto
+}
+
+// PipeFrom runs the StreamingPreprocessor over records received on in and returns a channel of finalized
+// records, preserving order. When in is closed, PipeFrom flushes any remaining provisional records and
+// closes the returned channel; a consumer that ranges over it will see every pushed record exactly once.
+func (s *StreamingPreprocessor) PipeFrom(in <-chan *activity.Record) <-chan *activity.Record {
+	out := make(chan *activity.Record)
+
+	go func() {
+		defer close(out)
+
+		for rec := range in {
+			for _, finalized := range s.Push(rec) {
+				out <- finalized
+			}
+		}
+		for _, finalized := range s.Flush() {
+			out <- finalized
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,179 @@
+package preprocessor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/muktihari/openactivity-fit/activity"
+)
+
+// streamingSource builds the same 200-record synthetic profile used by the smoother tests, suitable for
+// pushing one record at a time.
+func streamingSource() []*activity.Record {
+	_, records := syntheticProfile(200, 2)
+	return records
+}
+
+func TestStreamingPreprocessor_MatchesBatch(t *testing.T) {
+	const sport = "running"
+
+	source := streamingSource()
+
+	batch := make([]*activity.Record, len(source))
+	copy(batch, source)
+	p := New()
+	p.CalculateDistanceAndSpeed(sport, batch)
+	p.SmoothingElev(sport, batch)
+	p.CalculateGrade(sport, batch)
+	p.CalculatePace(sport, batch)
+
+	sp := NewStreaming(sport)
+	var streamed []*activity.Record
+	for _, rec := range source {
+		streamed = append(streamed, sp.Push(rec)...)
+	}
+	streamed = append(streamed, sp.Flush()...)
+
+	if len(streamed) != len(batch) {
+		t.Fatalf("got %d streamed records, want %d", len(streamed), len(batch))
+	}
+
+	for i := range batch {
+		want, got := batch[i], streamed[i]
+		if !want.Timestamp.Equal(got.Timestamp) {
+			t.Fatalf("record %d: timestamp = %v, want %v", i, got.Timestamp, want.Timestamp)
+		}
+		if (want.Grade == nil) != (got.Grade == nil) {
+			t.Fatalf("record %d: Grade = %v, want %v", i, got.Grade, want.Grade)
+		}
+		if want.Grade != nil && *want.Grade != *got.Grade {
+			t.Errorf("record %d: Grade = %v, want %v", i, *got.Grade, *want.Grade)
+		}
+		if want.Altitude != nil && got.Altitude != nil && *want.Altitude != *got.Altitude {
+			t.Errorf("record %d: Altitude = %v, want %v", i, *got.Altitude, *want.Altitude)
+		}
+	}
+}
+
+// TestStreamingPreprocessor_MatchesBatch_NonDefaultSmoother pushes the same synthetic profile used by
+// TestStreamingPreprocessor_MatchesBatch through the streaming path configured with SGSmoother and
+// KalmanSmoother (see WithElevationSmoother), and checks the result matches a batch run exactly. This
+// guards against the smoothers losing filter state or misjudging a resident window's edge for a true
+// activity boundary across repeated Push calls (see KalmanSmoother.SmoothTail, SGSmoother.SmoothTail).
+func TestStreamingPreprocessor_MatchesBatch_NonDefaultSmoother(t *testing.T) {
+	const sport = "running"
+
+	sg, err := NewSGSmoother(11, 3)
+	if err != nil {
+		t.Fatalf("NewSGSmoother() error = %v", err)
+	}
+
+	smoothers := []struct {
+		name string
+		s    Smoother
+	}{
+		{"SavitzkyGolay", sg},
+		{"Kalman", NewKalmanSmoother(0.02, 1)},
+	}
+
+	for _, tc := range smoothers {
+		t.Run(tc.name, func(t *testing.T) {
+			source := streamingSource()
+
+			batch := make([]*activity.Record, len(source))
+			copy(batch, source)
+			p := New(WithElevationSmoother(tc.s))
+			p.CalculateDistanceAndSpeed(sport, batch)
+			p.SmoothingElev(sport, batch)
+			p.CalculateGrade(sport, batch)
+			p.CalculatePace(sport, batch)
+
+			sp := NewStreaming(sport, WithElevationSmoother(tc.s))
+			var streamed []*activity.Record
+			for _, rec := range source {
+				streamed = append(streamed, sp.Push(rec)...)
+			}
+			streamed = append(streamed, sp.Flush()...)
+
+			if len(streamed) != len(batch) {
+				t.Fatalf("got %d streamed records, want %d", len(streamed), len(batch))
+			}
+
+			for i := range batch {
+				want, got := batch[i], streamed[i]
+				if (want.Altitude == nil) != (got.Altitude == nil) {
+					t.Fatalf("record %d: Altitude = %v, want %v", i, got.Altitude, want.Altitude)
+				}
+				if want.Altitude != nil && *want.Altitude != *got.Altitude {
+					t.Errorf("record %d: Altitude = %v, want %v", i, *got.Altitude, *want.Altitude)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamingPreprocessor_PushWithheldUntilFlush(t *testing.T) {
+	sp := NewStreaming("running")
+
+	source := streamingSource()[:5]
+	var gotFromPush int
+	for _, rec := range source {
+		gotFromPush += len(sp.Push(rec))
+	}
+	if gotFromPush != 0 {
+		t.Fatalf("Push returned %d records before enough forward distance accumulated, want 0", gotFromPush)
+	}
+
+	if got := len(sp.Flush()); got != len(source) {
+		t.Fatalf("Flush returned %d records, want %d", got, len(source))
+	}
+}
+
+// TestStreamingPreprocessor_NonLocomotiveEmitsEagerly guards against a non-locomotive stream (see
+// activity.IsNonLocomotive) buffering the entire workout: since CalculateDistanceAndSpeed is a no-op for
+// these sports, readiness can't be gated on Distance and must instead be gated on timestamps (see
+// readyCountNonLocomotive).
+func TestStreamingPreprocessor_NonLocomotiveEmitsEagerly(t *testing.T) {
+	sp := NewStreaming("weight_training")
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := make([]*activity.Record, 5)
+	for i := range records {
+		records[i] = &activity.Record{Timestamp: base.Add(time.Duration(i) * time.Second)}
+	}
+
+	var gotFromPush int
+	for _, rec := range records {
+		gotFromPush += len(sp.Push(rec))
+	}
+	if gotFromPush != len(records)-1 {
+		t.Fatalf("Push returned %d records before Flush, want %d", gotFromPush, len(records)-1)
+	}
+
+	if got := len(sp.Flush()); got != 1 {
+		t.Fatalf("Flush returned %d records, want 1", got)
+	}
+}
+
+func TestStreamingPreprocessor_PipeFrom(t *testing.T) {
+	sp := NewStreaming("running")
+
+	in := make(chan *activity.Record)
+	out := sp.PipeFrom(in)
+
+	go func() {
+		defer close(in)
+		for _, rec := range streamingSource() {
+			in <- rec
+		}
+	}()
+
+	var n int
+	for range out {
+		n++
+	}
+
+	if n != len(streamingSource()) {
+		t.Errorf("PipeFrom emitted %d records, want %d", n, len(streamingSource()))
+	}
+}